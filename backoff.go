@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff decides how long to wait before the next attempt. attempt is
+// the 1-indexed number of the attempt that just failed, and lastErr is
+// the error that attempt returned, so a Backoff implementation can vary
+// the interval based on why the previous attempt failed.
+type Backoff interface {
+	NextInterval(attempt int, lastErr error) time.Duration
+}
+
+// ConstantBackoff waits the same Interval before every attempt. This is
+// the strategy used when a Retry is configured with Sleep().
+type ConstantBackoff struct {
+	Interval time.Duration
+}
+
+// NextInterval always returns Interval, regardless of the attempt.
+func (b ConstantBackoff) NextInterval(attempt int, lastErr error) time.Duration {
+	return b.Interval
+}
+
+// LinearBackoff grows the wait time linearly with the attempt number:
+// Interval, 2*Interval, 3*Interval, and so on.
+type LinearBackoff struct {
+	Interval time.Duration
+}
+
+// NextInterval returns Interval multiplied by attempt.
+func (b LinearBackoff) NextInterval(attempt int, lastErr error) time.Duration {
+	return time.Duration(attempt) * b.Interval
+}
+
+// ExponentialBackoff doubles the wait time on every attempt, starting
+// at Base and never going over Max. When Jitter is true, the returned
+// interval is chosen uniformly between zero and the computed exponential
+// value, which spreads out retries from many clients hitting the same
+// failure at once (the "full jitter" strategy).
+type ExponentialBackoff struct {
+	Base   time.Duration
+	Max    time.Duration
+	Jitter bool
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewExponentialBackoff creates an ExponentialBackoff capped at max,
+// starting at base, without jitter. Set the Jitter field on the
+// returned value to enable full jitter.
+func NewExponentialBackoff(base, max time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{Base: base, Max: max}
+}
+
+// NextInterval returns base * 2^(attempt-1), capped at Max, optionally
+// randomized down to a uniform value between zero and that cap.
+func (b *ExponentialBackoff) NextInterval(attempt int, lastErr error) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	exp := b.Base * time.Duration(1<<uint(attempt-1))
+	if exp <= 0 || exp > b.Max {
+		exp = b.Max
+	}
+
+	if !b.Jitter || exp <= 0 {
+		return exp
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rnd == nil {
+		b.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return time.Duration(b.rnd.Int63n(int64(exp)))
+}
+
+// DecorrelatedJitterBackoff implements the AWS-style "decorrelated
+// jitter" strategy: each interval is randomized relative to the
+// previous one rather than to a fixed exponential curve, which avoids
+// the thundering-herd effect better than plain exponential backoff.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Cap  time.Duration
+
+	mu   sync.Mutex
+	prev time.Duration
+	rnd  *rand.Rand
+}
+
+// NewDecorrelatedJitterBackoff creates a DecorrelatedJitterBackoff
+// seeded from the current time, starting at base and never exceeding
+// cap.
+func NewDecorrelatedJitterBackoff(base, cap time.Duration) *DecorrelatedJitterBackoff {
+	return &DecorrelatedJitterBackoff{
+		Base: base,
+		Cap:  cap,
+		prev: base,
+		rnd:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// NextInterval returns min(cap, random(base, prev*3)), then remembers
+// the result as prev for the next call.
+func (b *DecorrelatedJitterBackoff) NextInterval(attempt int, lastErr error) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	upper := int64(b.prev)*3 - int64(b.Base)
+	if upper <= 0 {
+		upper = int64(b.Base)
+	}
+
+	// Base (and therefore upper) can legitimately be zero, for a
+	// "retry immediately" config. rand.Int63n panics on a non-positive
+	// argument, so skip it and fall straight to Base in that case.
+	next := b.Base
+	if upper > 0 {
+		if b.rnd == nil {
+			b.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+
+		next = time.Duration(b.rnd.Int63n(upper)) + b.Base
+	}
+
+	if next > b.Cap {
+		next = b.Cap
+	}
+
+	b.prev = next
+
+	return next
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	b := ConstantBackoff{Interval: 250 * time.Millisecond}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if got := b.NextInterval(attempt, nil); got != b.Interval {
+			t.Fatalf("ConstantBackoff attempt %d: expected %v, got %v", attempt, b.Interval, got)
+		}
+	}
+}
+
+func TestLinearBackoff(t *testing.T) {
+	b := LinearBackoff{Interval: 100 * time.Millisecond}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		want := time.Duration(attempt) * b.Interval
+		if got := b.NextInterval(attempt, nil); got != want {
+			t.Fatalf("LinearBackoff attempt %d: expected %v, got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := NewExponentialBackoff(10*time.Millisecond, time.Second)
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{3, 40 * time.Millisecond},
+	}
+
+	for _, c := range cases {
+		if got := b.NextInterval(c.attempt, nil); got != c.want {
+			t.Fatalf("ExponentialBackoff attempt %d: expected %v, got %v", c.attempt, c.want, got)
+		}
+	}
+}
+
+func TestExponentialBackoffCap(t *testing.T) {
+	b := NewExponentialBackoff(10*time.Millisecond, 25*time.Millisecond)
+
+	if got := b.NextInterval(10, nil); got != b.Max {
+		t.Fatalf("ExponentialBackoff expected to cap at %v, got %v", b.Max, got)
+	}
+}
+
+func TestExponentialBackoffJitter(t *testing.T) {
+	b := NewExponentialBackoff(10*time.Millisecond, time.Second)
+	b.Jitter = true
+
+	for i := 0; i < 20; i++ {
+		got := b.NextInterval(5, nil)
+		if got < 0 || got > b.Max {
+			t.Fatalf("ExponentialBackoff with jitter returned %v, outside [0, %v]", got, b.Max)
+		}
+	}
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	b := NewDecorrelatedJitterBackoff(10*time.Millisecond, time.Second)
+
+	for i := 0; i < 20; i++ {
+		got := b.NextInterval(i+1, nil)
+		if got < b.Base || got > b.Cap {
+			t.Fatalf("DecorrelatedJitterBackoff returned %v, outside [%v, %v]", got, b.Base, b.Cap)
+		}
+	}
+}
+
+func TestDecorrelatedJitterBackoffZeroBase(t *testing.T) {
+	// A zero Base used to panic with "invalid argument to Int63n"
+	// because the randomized range collapsed to zero.
+	b := NewDecorrelatedJitterBackoff(0, 100*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		got := b.NextInterval(i+1, nil)
+		if got < 0 || got > b.Cap {
+			t.Fatalf("DecorrelatedJitterBackoff with zero Base returned %v, outside [0, %v]", got, b.Cap)
+		}
+	}
+}
+
+func TestDecorrelatedJitterBackoffStructLiteral(t *testing.T) {
+	// Constructed as a bare struct literal instead of through
+	// NewDecorrelatedJitterBackoff, rnd is nil. NextInterval used to
+	// dereference it unconditionally and panic on the first call.
+	b := &DecorrelatedJitterBackoff{Base: 10 * time.Millisecond, Cap: time.Second}
+
+	for i := 0; i < 5; i++ {
+		got := b.NextInterval(i+1, nil)
+		if got < b.Base || got > b.Cap {
+			t.Fatalf("DecorrelatedJitterBackoff struct literal returned %v, outside [%v, %v]", got, b.Base, b.Cap)
+		}
+	}
+}
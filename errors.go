@@ -0,0 +1,38 @@
+package retry
+
+import "errors"
+
+// permanentError marks an error as non-retriable. It's created by
+// PermanentError and unwrapped by IsPermanent.
+type permanentError struct {
+	err error
+}
+
+// Error returns the wrapped error's message.
+func (p *permanentError) Error() string {
+	return p.err.Error()
+}
+
+// Unwrap returns the error passed to PermanentError, so callers can
+// still use errors.Is/errors.As against it.
+func (p *permanentError) Unwrap() error {
+	return p.err
+}
+
+// PermanentError wraps err so that DoErr stops retrying and returns it
+// immediately instead of attempting again, the same way returning Stop
+// does for Operation. Passing nil returns nil.
+func PermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err (or something it wraps) was created
+// with PermanentError.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
@@ -0,0 +1,31 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPermanentError(t *testing.T) {
+	cause := errors.New("boom")
+	err := PermanentError(cause)
+
+	if !IsPermanent(err) {
+		t.Fatalf("IsPermanent expected true for a PermanentError-wrapped error")
+	}
+
+	if !errors.Is(err, cause) {
+		t.Fatalf("errors.Is expected PermanentError to unwrap to its cause")
+	}
+}
+
+func TestPermanentErrorNil(t *testing.T) {
+	if err := PermanentError(nil); err != nil {
+		t.Fatalf("PermanentError(nil) expected nil, got %v", err)
+	}
+}
+
+func TestIsPermanentFalseForPlainError(t *testing.T) {
+	if IsPermanent(errors.New("boom")) {
+		t.Fatalf("IsPermanent expected false for a plain error")
+	}
+}
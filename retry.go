@@ -1,7 +1,9 @@
 package retry
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,7 +16,11 @@ var ErrOSRequestedCancellation = errors.New("received OS signal to stop operatio
 
 // ErrRetriesExhausted gets returned from the Do() operation when
 // the maximum amount of retries gets exhausted and no more retries
-// are attempted
+// are attempted. The returned error wraps ErrRetriesExhausted together
+// with an errors.Join of every non-nil error the callback returned
+// across attempts, so errors.Is(err, ErrRetriesExhausted) still works
+// and errors.Is/errors.As can also match against any individual
+// attempt's error.
 var ErrRetriesExhausted = errors.New("exhausted the number of retries")
 
 // Reason is a custom type to pass to
@@ -36,15 +42,46 @@ const (
 // action again or just stop and continue.
 type Operation func() (Reason, error)
 
+// OperationErr is a plain error-returning callback, for callers who'd
+// rather not hand-roll the Reason switch that Operation requires. Use
+// it with DoErr: a nil error stops successfully, an error wrapped with
+// PermanentError stops immediately, and any other error is retried
+// until exhaustion unless RetryIf rejects it.
+type OperationErr func() error
+
 // Config is a custom type for functions that accept retry
 type Config func(*Retry)
 
 // Retry is a package that allows to execute a callback function multiple
 // times until that function thinks it's safe to stop. The flow is handled by returning
 // a reason, either Retry or Stop.
+//
+// Once constructed, a *Retry is never mutated again, so a single
+// instance is safe to share and call Do/DoContext/DoErr/DoHinted... on
+// concurrently from multiple goroutines. The Backoff implementations
+// this package ships with are also safe for that kind of concurrent
+// reuse.
 type Retry struct {
-	sleep       time.Duration
+	backoff     Backoff
 	maxattempts int
+	retryIf     func(error) bool
+	maxdelay    time.Duration
+	onretry     func(Attempt)
+}
+
+// Attempt describes a single failed attempt, passed to the OnRetry
+// hook before the scheduler sleeps. This includes the final attempt
+// allowed by MaxAttempts, after which no further attempt is made.
+type Attempt struct {
+	// Number is the 1-indexed attempt that just failed.
+	Number int
+
+	// Elapsed is the time spent since the Do/DoContext/... call
+	// started, including previous sleeps.
+	Elapsed time.Duration
+
+	// LastErr is the error the callback returned for this attempt.
+	LastErr error
 }
 
 // MaxAttempts return the currently set number of attempts
@@ -57,7 +94,7 @@ func (r *Retry) MaxAttempts() int {
 // change by passing a RetryConfig with the Sleep option.
 func New(options ...Config) *Retry {
 	current := Retry{
-		sleep: 5 * time.Second,
+		backoff: ConstantBackoff{Interval: 5 * time.Second},
 	}
 
 	for _, opt := range options {
@@ -67,11 +104,37 @@ func New(options ...Config) *Retry {
 	return &current
 }
 
+// Must is an alias for New, named to read naturally next to
+// retry.Default: retry.Must(options...).Do(...). Unlike other Must*
+// constructors in the standard library, it never panics — there's no
+// Config option in this package that can fail to apply.
+func Must(options ...Config) *Retry {
+	return New(options...)
+}
+
+// Default is a ready-to-use Retry with the same settings as New()
+// with no options: a 5 second constant backoff and unlimited attempts.
+// It lets callers reach for retry.Default.Do(...) without constructing
+// their own *Retry first.
+var Default = New()
+
 // Sleep is a retry configuration you can pass to the New()
-// function to change the default sleep time per iteration.
+// function to change the default sleep time per iteration. It's sugar
+// for WithBackoff(ConstantBackoff{Interval: d}); use WithBackoff
+// directly if you need a strategy other than a constant interval.
 func Sleep(d time.Duration) Config {
 	return func(r *Retry) {
-		r.sleep = d
+		r.backoff = ConstantBackoff{Interval: d}
+	}
+}
+
+// WithBackoff is a retry configuration you can pass to the New()
+// function to pick the strategy used to compute the wait time between
+// attempts. See ConstantBackoff, LinearBackoff, ExponentialBackoff and
+// DecorrelatedJitterBackoff for the strategies this package ships with.
+func WithBackoff(b Backoff) Config {
+	return func(r *Retry) {
+		r.backoff = b
 	}
 }
 
@@ -84,58 +147,194 @@ func MaxAttempts(attempts int) Config {
 	}
 }
 
-// Do retries a RetryOperation until either retry.Stop or retry.Again
-// gets returned. Stop will continue the parent flow and Again will execute
-// the RetryOperation again.
-func (r *Retry) Do(fn Operation) error {
-	// Wait until you get something here
-	close := make(chan error, 1)
-
-	// Create a goroutine to handle the operation loop
-	go func(callback Operation, ch chan error) {
-		// Start with a single attempt
-		attempts := 1
-		for {
-			// If the attempts were set to something other than zero
-			// and we hit the maximum number of attempts, then exhaust
-			// and return
-			if r.maxattempts != 0 && attempts > r.maxattempts {
-				ch <- ErrRetriesExhausted
-				return
+// RetryIf is a retry configuration you can pass to the New() function
+// to decide, per error, whether DoErr should retry or stop. When not
+// set, every non-permanent error is retried until exhaustion.
+func RetryIf(fn func(error) bool) Config {
+	return func(r *Retry) {
+		r.retryIf = fn
+	}
+}
+
+// MaxDelay is a retry configuration you can pass to the New() function
+// to cap the wait time DoHinted uses when an Operation reports its own
+// delay, for example from an HTTP Retry-After header. It has no effect
+// on the configured Backoff. Zero, the default, leaves hinted delays
+// uncapped.
+func MaxDelay(d time.Duration) Config {
+	return func(r *Retry) {
+		r.maxdelay = d
+	}
+}
+
+// OnRetry is a retry configuration you can pass to the New() function
+// to observe every attempt that returned Again, before the scheduler
+// sleeps, for circuit-breaker logic or structured retry logging. It
+// fires for the final attempt allowed by MaxAttempts too, even though
+// no further attempt follows.
+func OnRetry(fn func(Attempt)) Config {
+	return func(r *Retry) {
+		r.onretry = fn
+	}
+}
+
+// DoErr retries an OperationErr based on the error it returns: nil
+// stops successfully, an error wrapped with PermanentError stops
+// immediately, and any other error is retried until exhaustion unless
+// a RetryIf predicate rejects it.
+func (r *Retry) DoErr(fn OperationErr) error {
+	return r.Do(func() (Reason, error) {
+		err := fn()
+		if err == nil {
+			return Stop, nil
+		}
+
+		if IsPermanent(err) {
+			return Stop, err
+		}
+
+		if r.retryIf != nil && !r.retryIf(err) {
+			return Stop, err
+		}
+
+		return Again, err
+	})
+}
+
+// HintedOperation is an Operation that can override the backoff's
+// computed wait time for its next attempt, for example to honor an
+// HTTP Retry-After header or a gRPC RetryInfo trailer. Returning a
+// delay of zero or less falls back to the configured Backoff.
+type HintedOperation func() (Reason, time.Duration, error)
+
+// step is the shape shared by Operation and HintedOperation once
+// adapted for the scheduler: a Reason, an optional delay hint, and the
+// error for that attempt.
+type step func(attempt int) (Reason, time.Duration, error)
+
+// run drives fn to completion, sleeping between Again attempts for
+// either the hint fn provides (capped by maxdelay when set) or, if the
+// hint is zero, the configured Backoff's interval, which maxdelay does
+// not affect. It respects ctx cancellation both between attempts and
+// while sleeping.
+func (r *Retry) run(ctx context.Context, fn step) error {
+	// Start with a single attempt
+	attempts := 1
+	start := time.Now()
+	var errs []error
+
+	for {
+		// If the attempts were set to something other than zero
+		// and we hit the maximum number of attempts, then exhaust
+		// and return, joining every error the callback returned
+		// along the way so callers can inspect the full history.
+		if r.maxattempts != 0 && attempts > r.maxattempts {
+			if len(errs) > 0 {
+				return fmt.Errorf("%w: %w", ErrRetriesExhausted, errors.Join(errs...))
+			}
+
+			return ErrRetriesExhausted
+		}
+
+		// Bail out early if the context is already done, so we don't
+		// call the operation one extra time after cancellation.
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("retry: context cancelled: %w", err)
+		}
+
+		// Call the user-given function
+		reason, hint, err := fn(attempts)
+		if err != nil && r.maxattempts != 0 {
+			// Only accumulated when attempts are bounded: with
+			// unlimited attempts (the default) this would otherwise
+			// grow without bound for the lifetime of the call.
+			errs = append(errs, err)
+		}
+
+		// Check the returned values
+		switch reason {
+		case Stop:
+			return err
+		case Again:
+			if r.onretry != nil {
+				r.onretry(Attempt{Number: attempts, Elapsed: time.Since(start), LastErr: err})
 			}
 
-			// Call the user-given function
-			reason, err := callback()
-
-			// Check the returned values
-			switch reason {
-			case Stop:
-				ch <- err
-				return
-			case Again:
-				time.Sleep(r.sleep)
-				attempts++
-				continue
+			var interval time.Duration
+			if hint > 0 {
+				interval = hint
+				if r.maxdelay > 0 && interval > r.maxdelay {
+					interval = r.maxdelay
+				}
+			} else {
+				interval = r.backoff.NextInterval(attempts, err)
 			}
+
+			timer := time.NewTimer(interval)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("retry: context cancelled: %w", ctx.Err())
+			case <-timer.C:
+			}
+
+			attempts++
+			continue
 		}
-	}(fn, close)
-
-	// This goroutine will wait for a OS Signal to come by
-	go func(ch chan error) {
-		// Create an OS signal, so if terraform tells us to stop
-		// we don't try again. This will wait until fn() goes to the next loop
-		finish := make(chan os.Signal, 1)
-		signal.Notify(finish, os.Interrupt, syscall.SIGTERM)
-
-		// This will block here until we get a signal, it won't
-		// go to the line below unless the signal happen
-		<-finish
-
-		// If we reach this line, it means we did received a signal, so we need
-		// to exit per OS request
-		ch <- ErrOSRequestedCancellation
-	}(close)
-
-	// Retrieve the value we will return
-	return <-close
+	}
+}
+
+// DoContext retries an Operation until either retry.Stop or retry.Again
+// gets returned, respecting ctx cancellation both between attempts and
+// while sleeping. If ctx is cancelled or its deadline is exceeded before
+// the operation settles, DoContext returns an error that satisfies
+// errors.Is(err, ctx.Err()).
+func (r *Retry) DoContext(ctx context.Context, fn Operation) error {
+	return r.run(ctx, func(int) (Reason, time.Duration, error) {
+		reason, err := fn()
+		return reason, 0, err
+	})
+}
+
+// DoHintedContext behaves like DoContext, but fn can override the wait
+// time used before its next attempt instead of relying solely on the
+// configured Backoff.
+func (r *Retry) DoHintedContext(ctx context.Context, fn HintedOperation) error {
+	return r.run(ctx, func(int) (Reason, time.Duration, error) {
+		return fn()
+	})
+}
+
+// Do retries an Operation until either retry.Stop or retry.Again gets
+// returned. Stop will continue the parent flow and Again will execute
+// the Operation again. Do composes a context.Context cancelled by
+// SIGINT or SIGTERM, so the operation stops as soon as the OS asks the
+// process to terminate.
+func (r *Retry) Do(fn Operation) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := r.DoContext(ctx, fn)
+	if err != nil && ctx.Err() != nil {
+		return ErrOSRequestedCancellation
+	}
+
+	return err
+}
+
+// DoHinted behaves like Do, but fn can override the wait time used
+// before its next attempt instead of relying solely on the configured
+// Backoff, which is how a caller implements support for an HTTP
+// Retry-After header or similar "try again in N" response.
+func (r *Retry) DoHinted(fn HintedOperation) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := r.DoHintedContext(ctx, fn)
+	if err != nil && ctx.Err() != nil {
+		return ErrOSRequestedCancellation
+	}
+
+	return err
 }
@@ -1,6 +1,8 @@
 package retry
 
 import (
+	"context"
+	"errors"
 	"math/rand"
 	"testing"
 	"time"
@@ -60,3 +62,246 @@ func TestRetryMaxAttempts(t *testing.T) {
 		t.Fatalf("Retry should've counted %v attempts, but did %v instead.", total, count)
 	}
 }
+
+func TestDoContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := New(Sleep(1 * time.Millisecond))
+
+	err := r.DoContext(ctx, func() (Reason, error) {
+		return Again, nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("DoContext expected an error wrapping context.Canceled, got %v", err)
+	}
+}
+
+func TestDoContextCancelledDuringSleep(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	r := New(Sleep(1 * time.Hour))
+	count := 0
+
+	err := r.DoContext(ctx, func() (Reason, error) {
+		count++
+		return Again, nil
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DoContext expected an error wrapping context.DeadlineExceeded, got %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("DoContext expected the operation to run exactly once before the deadline, ran %v times", count)
+	}
+}
+
+func TestDoErrStopsOnNil(t *testing.T) {
+	count := 0
+	r := New(Sleep(1 * time.Millisecond))
+
+	err := r.DoErr(func() error {
+		count++
+		if count == 3 {
+			return nil
+		}
+
+		return errors.New("not yet")
+	})
+
+	if err != nil {
+		t.Fatalf("DoErr expected no error, got %v", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("DoErr expected 3 attempts, got %v", count)
+	}
+}
+
+func TestDoErrStopsOnPermanentError(t *testing.T) {
+	count := 0
+	cause := errors.New("permanent failure")
+	r := New(Sleep(1 * time.Millisecond), MaxAttempts(10))
+
+	err := r.DoErr(func() error {
+		count++
+		return PermanentError(cause)
+	})
+
+	if !errors.Is(err, cause) {
+		t.Fatalf("DoErr expected an error wrapping %v, got %v", cause, err)
+	}
+
+	if count != 1 {
+		t.Fatalf("DoErr expected to stop after the first permanent error, ran %v times", count)
+	}
+}
+
+func TestDoErrRetryIfRejectsError(t *testing.T) {
+	count := 0
+	cause := errors.New("not retriable")
+	r := New(Sleep(1*time.Millisecond), MaxAttempts(10), RetryIf(func(err error) bool {
+		return err != cause
+	}))
+
+	err := r.DoErr(func() error {
+		count++
+		return cause
+	})
+
+	if !errors.Is(err, cause) {
+		t.Fatalf("DoErr expected to return %v, got %v", cause, err)
+	}
+
+	if count != 1 {
+		t.Fatalf("DoErr expected RetryIf to stop retrying after the first attempt, ran %v times", count)
+	}
+}
+
+func TestDoHintedUsesHintedDelay(t *testing.T) {
+	var delays []time.Duration
+	var last time.Time
+	count := 0
+	r := New(Sleep(1 * time.Hour))
+
+	r.DoHinted(func() (Reason, time.Duration, error) {
+		now := time.Now()
+		if count > 0 {
+			delays = append(delays, now.Sub(last))
+		}
+		last = now
+
+		count++
+		if count == 3 {
+			return Stop, 0, nil
+		}
+
+		return Again, 5 * time.Millisecond, nil
+	})
+
+	if count != 3 {
+		t.Fatalf("DoHinted expected 3 attempts, got %v", count)
+	}
+
+	for _, d := range delays {
+		if d < 5*time.Millisecond || d >= time.Second {
+			t.Fatalf("DoHinted expected every delay between attempts to be the hinted 5ms, not the 1h backoff, got %v", d)
+		}
+	}
+}
+
+func TestDoHintedMaxDelayClampsHint(t *testing.T) {
+	count := 0
+	r := New(Sleep(1*time.Hour), MaxDelay(5*time.Millisecond))
+
+	start := time.Now()
+
+	r.DoHinted(func() (Reason, time.Duration, error) {
+		count++
+		if count == 2 {
+			return Stop, 0, nil
+		}
+
+		return Again, time.Hour, nil
+	})
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("DoHinted expected MaxDelay to clamp the 1h hint down to ~5ms, took %v", elapsed)
+	}
+}
+
+func TestMaxDelayDoesNotAffectBackoff(t *testing.T) {
+	// MaxDelay must only clamp a hinted delay, never the configured
+	// Backoff used by plain DoContext/Do.
+	r := New(WithBackoff(ConstantBackoff{Interval: 20 * time.Millisecond}), MaxDelay(1*time.Millisecond), MaxAttempts(2))
+
+	start := time.Now()
+
+	r.DoContext(context.Background(), func() (Reason, error) {
+		return Again, nil
+	})
+
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Fatalf("DoContext expected the unclamped 20ms Backoff interval to apply, only took %v", elapsed)
+	}
+}
+
+func TestOnRetryReceivesAttempts(t *testing.T) {
+	var attempts []Attempt
+	r := New(Sleep(1*time.Millisecond), MaxAttempts(3), OnRetry(func(a Attempt) {
+		attempts = append(attempts, a)
+	}))
+
+	r.Do(func() (Reason, error) {
+		return Again, errors.New("still failing")
+	})
+
+	if len(attempts) != 3 {
+		t.Fatalf("OnRetry expected to fire for all 3 attempts, fired %v times", len(attempts))
+	}
+
+	for i, a := range attempts {
+		if a.Number != i+1 {
+			t.Fatalf("OnRetry attempt %d: expected Number %d, got %d", i, i+1, a.Number)
+		}
+
+		if a.LastErr == nil {
+			t.Fatalf("OnRetry attempt %d: expected a non-nil LastErr", i)
+		}
+	}
+}
+
+func TestExhaustedErrorJoinsCallbackErrors(t *testing.T) {
+	first := errors.New("first failure")
+	second := errors.New("second failure")
+	errs := []error{first, second}
+	call := 0
+
+	r := New(Sleep(1*time.Millisecond), MaxAttempts(2))
+
+	err := r.Do(func() (Reason, error) {
+		e := errs[call]
+		call++
+		return Again, e
+	})
+
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("expected errors.Is(err, ErrRetriesExhausted) to hold, got %v", err)
+	}
+
+	if !errors.Is(err, first) {
+		t.Fatalf("expected the exhausted error to wrap %v, got %v", first, err)
+	}
+
+	if !errors.Is(err, second) {
+		t.Fatalf("expected the exhausted error to wrap %v, got %v", second, err)
+	}
+}
+
+func TestMustBuildsAnEquivalentRetry(t *testing.T) {
+	r := Must(MaxAttempts(7))
+
+	if r.MaxAttempts() != 7 {
+		t.Fatalf("Must expected MaxAttempts to be 7, got %v", r.MaxAttempts())
+	}
+}
+
+func TestDefaultIsUsable(t *testing.T) {
+	count := 0
+
+	err := Default.DoErr(func() error {
+		count++
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Default.DoErr expected no error, got %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("Default.DoErr expected the operation to run once, ran %v times", count)
+	}
+}